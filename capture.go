@@ -0,0 +1,66 @@
+package traefikrequestlogger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// captureResponseWriter wraps an http.ResponseWriter so the logger can
+// observe the status code and byte count written downstream, without
+// changing the behaviour seen by next. It implements the optional
+// http.Flusher, http.Hijacker and http.CloseNotifier interfaces, falling
+// back gracefully when the wrapped writer doesn't support them.
+type captureResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func newCaptureResponseWriter(rw http.ResponseWriter) *captureResponseWriter {
+	return &captureResponseWriter{ResponseWriter: rw, status: http.StatusOK}
+}
+
+func (c *captureResponseWriter) WriteHeader(status int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *captureResponseWriter) Write(p []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	n, err := c.ResponseWriter.Write(p)
+	c.size += int64(n)
+	return n, err
+}
+
+func (c *captureResponseWriter) Flush() {
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (c *captureResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not implement http.Hijacker", c.ResponseWriter)
+	}
+	return hijacker.Hijack()
+}
+
+// CloseNotify is deprecated upstream but still implemented here so the
+// wrapper keeps satisfying http.CloseNotifier for handlers that rely on it.
+func (c *captureResponseWriter) CloseNotify() <-chan bool {
+	if notifier, ok := c.ResponseWriter.(http.CloseNotifier); ok { //nolint:staticcheck
+		return notifier.CloseNotify()
+	}
+	ch := make(chan bool, 1)
+	return ch
+}
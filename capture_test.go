@@ -0,0 +1,59 @@
+package traefikrequestlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureResponseWriter_DefaultStatus(t *testing.T) {
+	rr := httptest.NewRecorder()
+	capture := newCaptureResponseWriter(rr)
+
+	n, err := capture.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, http.StatusOK, capture.status)
+	assert.EqualValues(t, 5, capture.size)
+}
+
+func TestCaptureResponseWriter_ExplicitStatus(t *testing.T) {
+	rr := httptest.NewRecorder()
+	capture := newCaptureResponseWriter(rr)
+
+	capture.WriteHeader(http.StatusNotFound)
+	_, _ = capture.Write([]byte("not found"))
+	_, _ = capture.Write([]byte("!"))
+
+	assert.Equal(t, http.StatusNotFound, capture.status)
+	assert.EqualValues(t, len("not found!"), capture.size)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestCaptureResponseWriter_WriteHeaderOnlyAppliesOnce(t *testing.T) {
+	rr := httptest.NewRecorder()
+	capture := newCaptureResponseWriter(rr)
+
+	capture.WriteHeader(http.StatusTeapot)
+	capture.WriteHeader(http.StatusInternalServerError)
+
+	assert.Equal(t, http.StatusTeapot, capture.status)
+}
+
+func TestCaptureResponseWriter_Flush(t *testing.T) {
+	rr := httptest.NewRecorder()
+	capture := newCaptureResponseWriter(rr)
+
+	assert.NotPanics(t, capture.Flush)
+	assert.True(t, rr.Flushed)
+}
+
+func TestCaptureResponseWriter_HijackUnsupported(t *testing.T) {
+	rr := httptest.NewRecorder()
+	capture := newCaptureResponseWriter(rr)
+
+	_, _, err := capture.Hijack()
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package traefikrequestlogger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filters restricts which requests get logged, mirroring Traefik's own
+// access-log filter block. An entry is logged when it matches at least one
+// of the configured criteria; criteria left unset are ignored. When Filters
+// is nil, every entry is logged.
+type Filters struct {
+	// StatusCodes matches when DownstreamStatus falls in one of the given
+	// values, each either a single code ("500") or an inclusive range
+	// ("400-599").
+	StatusCodes []string `yaml:"statusCodes"`
+	// MinDuration matches when the request took at least this long,
+	// parsed with time.ParseDuration.
+	MinDuration string `yaml:"minDuration"`
+	// RetryAttempts matches any request that was retried at least once.
+	RetryAttempts bool `yaml:"retryAttempts"`
+}
+
+// statusRange is an inclusive [min, max] range of HTTP status codes.
+type statusRange struct {
+	min, max int
+}
+
+func (r statusRange) contains(status int) bool {
+	return status >= r.min && status <= r.max
+}
+
+// compiledFilters is the parsed, ready-to-evaluate form of Filters.
+type compiledFilters struct {
+	statusRanges  []statusRange
+	minDuration   time.Duration
+	retryAttempts bool
+}
+
+// parseFilters validates and compiles a Filters config. A nil Filters
+// compiles to a nil *compiledFilters, which matches every entry.
+func parseFilters(cfg *Filters) (*compiledFilters, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	compiled := &compiledFilters{retryAttempts: cfg.RetryAttempts}
+
+	for _, code := range cfg.StatusCodes {
+		r, err := parseStatusRange(code)
+		if err != nil {
+			return nil, err
+		}
+		compiled.statusRanges = append(compiled.statusRanges, r)
+	}
+
+	if cfg.MinDuration != "" {
+		d, err := time.ParseDuration(cfg.MinDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filters.minDuration %q: %w", cfg.MinDuration, err)
+		}
+		compiled.minDuration = d
+	}
+
+	return compiled, nil
+}
+
+func parseStatusRange(code string) (statusRange, error) {
+	before, after, found := strings.Cut(code, "-")
+	if !found {
+		status, err := strconv.Atoi(strings.TrimSpace(code))
+		if err != nil {
+			return statusRange{}, fmt.Errorf("invalid filters.statusCodes entry %q: %w", code, err)
+		}
+		return statusRange{min: status, max: status}, nil
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(before))
+	if err != nil {
+		return statusRange{}, fmt.Errorf("invalid filters.statusCodes entry %q: %w", code, err)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(after))
+	if err != nil {
+		return statusRange{}, fmt.Errorf("invalid filters.statusCodes entry %q: %w", code, err)
+	}
+	return statusRange{min: min, max: max}, nil
+}
+
+// matches reports whether entry should be logged. A nil compiledFilters (no
+// Filters configured) always matches.
+func (f *compiledFilters) matches(entry *logEntry) bool {
+	if f == nil {
+		return true
+	}
+
+	configured := false
+
+	if len(f.statusRanges) > 0 {
+		configured = true
+		for _, r := range f.statusRanges {
+			if r.contains(entry.DownstreamStatus) {
+				return true
+			}
+		}
+	}
+
+	if f.minDuration > 0 {
+		configured = true
+		if time.Duration(entry.Duration) >= f.minDuration {
+			return true
+		}
+	}
+
+	if f.retryAttempts {
+		configured = true
+		if entry.RetryAttempts > 0 {
+			return true
+		}
+	}
+
+	// Filters was configured but left every criterion unset: behave as if
+	// it weren't configured at all rather than silently dropping everything.
+	return !configured
+}
@@ -0,0 +1,58 @@
+package traefikrequestlogger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilters_Nil(t *testing.T) {
+	compiled, err := parseFilters(nil)
+	require.NoError(t, err)
+	assert.Nil(t, compiled)
+	assert.True(t, compiled.matches(&logEntry{}))
+}
+
+func TestParseFilters_InvalidStatusCode(t *testing.T) {
+	_, err := parseFilters(&Filters{StatusCodes: []string{"not-a-code"}})
+	assert.Error(t, err)
+}
+
+func TestParseFilters_InvalidMinDuration(t *testing.T) {
+	_, err := parseFilters(&Filters{MinDuration: "not-a-duration"})
+	assert.Error(t, err)
+}
+
+func TestCompiledFilters_StatusCodes(t *testing.T) {
+	compiled, err := parseFilters(&Filters{StatusCodes: []string{"500", "400-404"}})
+	require.NoError(t, err)
+
+	assert.True(t, compiled.matches(&logEntry{DownstreamStatus: 500}))
+	assert.True(t, compiled.matches(&logEntry{DownstreamStatus: 404}))
+	assert.False(t, compiled.matches(&logEntry{DownstreamStatus: 200}))
+}
+
+func TestCompiledFilters_MinDuration(t *testing.T) {
+	compiled, err := parseFilters(&Filters{MinDuration: "100ms"})
+	require.NoError(t, err)
+
+	assert.True(t, compiled.matches(&logEntry{Duration: (150 * time.Millisecond).Nanoseconds()}))
+	assert.False(t, compiled.matches(&logEntry{Duration: (50 * time.Millisecond).Nanoseconds()}))
+}
+
+func TestCompiledFilters_RetryAttempts(t *testing.T) {
+	compiled, err := parseFilters(&Filters{RetryAttempts: true})
+	require.NoError(t, err)
+
+	assert.True(t, compiled.matches(&logEntry{RetryAttempts: 1}))
+	assert.False(t, compiled.matches(&logEntry{RetryAttempts: 0}))
+}
+
+func TestCompiledFilters_EmptyConfigMatchesEverything(t *testing.T) {
+	compiled, err := parseFilters(&Filters{})
+	require.NoError(t, err)
+
+	assert.True(t, compiled.matches(&logEntry{DownstreamStatus: 200}))
+}
@@ -0,0 +1,117 @@
+package traefikrequestlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// logEntry carries everything a formatter needs to render a single access
+// log line. Fields that cannot be captured at a given point in the request
+// lifecycle are left at their zero value. The common and combined formatters
+// render a zero status as "-"; every other zero-valued field is rendered as
+// is (an empty string, or 0), and the JSON formatter substitutes nothing.
+type logEntry struct {
+	ClientAddr            string    `json:"ClientAddr"`
+	ClientHost            string    `json:"ClientHost"`
+	RequestMethod         string    `json:"RequestMethod"`
+	RequestHost           string    `json:"RequestHost"`
+	RequestPath           string    `json:"RequestPath"`
+	RequestProtocol       string    `json:"RequestProtocol"`
+	RequestScheme         string    `json:"RequestScheme"`
+	RequestReferer        string    `json:"RequestReferer"`
+	RequestUserAgent      string    `json:"RequestUserAgent"`
+	DownstreamStatus      int       `json:"DownstreamStatus"`
+	DownstreamContentSize int64     `json:"DownstreamContentSize"`
+	Duration              int64     `json:"Duration"`
+	StartUTC              time.Time `json:"StartUTC"`
+	RetryAttempts         int       `json:"RetryAttempts"`
+
+	// TLS and client-certificate fields, populated by populateTLSFields
+	// when the request was served over TLS. They are all left at their zero
+	// value for plain HTTP requests; over TLS without a client certificate,
+	// only TLSVersion and TLSCipher are set.
+	TLSVersion         string `json:"TLSVersion,omitempty"`
+	TLSCipher          string `json:"TLSCipher,omitempty"`
+	ClientCertCN       string `json:"ClientCertCN,omitempty"`
+	ClientCertOU       string `json:"ClientCertOU,omitempty"`
+	ClientCertSerial   string `json:"ClientCertSerial,omitempty"`
+	ClientCertNotAfter string `json:"ClientCertNotAfter,omitempty"`
+	ClientCertSubject  string `json:"ClientCertSubject,omitempty"`
+	ClientCertIssuer   string `json:"ClientCertIssuer,omitempty"`
+}
+
+// logFormatter renders a logEntry as a single line of text, ready to be
+// appended to the log buffer.
+type logFormatter interface {
+	Format(entry *logEntry) string
+}
+
+// newFormatter resolves the `Format` configuration value to a logFormatter,
+// falling back to the common log format when the value is empty or
+// unrecognized.
+func newFormatter(format string) logFormatter {
+	switch format {
+	case "combined":
+		return combinedFormatter{}
+	case "json":
+		return jsonFormatter{}
+	default:
+		return commonFormatter{}
+	}
+}
+
+const clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// commonFormatter renders entries in the NCSA Common Log Format:
+// remoteHost - user [time] "method path proto" status size
+type commonFormatter struct{}
+
+func (commonFormatter) Format(entry *logEntry) string {
+	return fmt.Sprintf("%s - - [%s] %q %s %s",
+		entry.ClientHost,
+		entry.StartUTC.Format(clfTimeLayout),
+		fmt.Sprintf("%s %s %s", entry.RequestMethod, entry.RequestPath, entry.RequestProtocol),
+		statusOrDash(entry.DownstreamStatus),
+		fmt.Sprintf("%d", entry.DownstreamContentSize),
+	)
+}
+
+// combinedFormatter renders entries in the Combined Log Format, which
+// extends commonFormatter with the referer and user-agent headers.
+type combinedFormatter struct{}
+
+func (combinedFormatter) Format(entry *logEntry) string {
+	return fmt.Sprintf("%s %q %q", commonFormatter{}.Format(entry), entry.RequestReferer, entry.RequestUserAgent)
+}
+
+// jsonFormatter marshals the entry as a single line of JSON, matching the
+// field vocabulary used by Traefik's own access-log middleware so the
+// output can be consumed by the same downstream tooling.
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(entry *logEntry) string {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error())
+	}
+	return string(b)
+}
+
+func statusOrDash(status int) string {
+	if status == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", status)
+}
+
+// clientHost strips the port from a host:port remote address, returning the
+// address unchanged when it has no port.
+func clientHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
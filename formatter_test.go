@@ -0,0 +1,59 @@
+package traefikrequestlogger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleEntry() *logEntry {
+	return &logEntry{
+		ClientAddr:       "192.168.1.1:12345",
+		ClientHost:       "192.168.1.1",
+		RequestMethod:    "GET",
+		RequestHost:      "example.com",
+		RequestPath:      "/test",
+		RequestProtocol:  "HTTP/1.1",
+		RequestScheme:    "http",
+		RequestReferer:   "https://example.com/",
+		RequestUserAgent: "test-agent",
+		StartUTC:         time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestNewFormatter(t *testing.T) {
+	assert.IsType(t, commonFormatter{}, newFormatter(""))
+	assert.IsType(t, commonFormatter{}, newFormatter("unknown"))
+	assert.IsType(t, commonFormatter{}, newFormatter("common"))
+	assert.IsType(t, combinedFormatter{}, newFormatter("combined"))
+	assert.IsType(t, jsonFormatter{}, newFormatter("json"))
+}
+
+func TestCommonFormatter(t *testing.T) {
+	line := commonFormatter{}.Format(sampleEntry())
+	assert.Equal(t, `192.168.1.1 - - [02/Jan/2024:03:04:05 +0000] "GET /test HTTP/1.1" - 0`, line)
+}
+
+func TestCombinedFormatter(t *testing.T) {
+	line := combinedFormatter{}.Format(sampleEntry())
+	assert.Contains(t, line, `"GET /test HTTP/1.1"`)
+	assert.Contains(t, line, `"https://example.com/"`)
+	assert.Contains(t, line, `"test-agent"`)
+}
+
+func TestJSONFormatter(t *testing.T) {
+	line := jsonFormatter{}.Format(sampleEntry())
+
+	var decoded logEntry
+	require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+	assert.Equal(t, "example.com", decoded.RequestHost)
+	assert.Equal(t, "GET", decoded.RequestMethod)
+}
+
+func TestClientHost(t *testing.T) {
+	assert.Equal(t, "192.168.1.1", clientHost("192.168.1.1:12345"))
+	assert.Equal(t, "no-port", clientHost("no-port"))
+}
@@ -5,14 +5,57 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
+// logFileFlags and logFilePerm are shared by New and Reopen so a reopened
+// file descriptor behaves identically to the one opened at startup.
+const (
+	logFileFlags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	logFilePerm  = 0644
+)
+
+// defaultBufferSize is used when Config.BufferSize is left unset.
+const defaultBufferSize = 4096
+
+// dropPolicy controls what happens when the entry buffer is full.
+type dropPolicy string
+
+const (
+	// dropPolicyBlock makes ServeHTTP wait for room in the buffer,
+	// trading request latency for not losing any log entries.
+	dropPolicyBlock dropPolicy = "block"
+	// dropPolicyDrop discards the entry instead of blocking the request,
+	// counting it in Stats().Dropped.
+	dropPolicyDrop dropPolicy = "drop"
+)
+
 // Config the plugin configuration.
 type Config struct {
 	Path     string `yaml:"path"`
 	Interval string `yaml:"interval"`
+	// Format selects the log line layout: "common" (NCSA Common Log
+	// Format), "combined" (common plus referer/user-agent), or "json".
+	// Defaults to "common".
+	Format string `yaml:"format"`
+	// Filters restricts which requests get logged. When nil, every
+	// request is logged.
+	Filters *Filters `yaml:"filters"`
+	// BufferSize is the number of formatted entries that can be queued
+	// for writing before DropPolicy kicks in. Defaults to 4096.
+	BufferSize int `yaml:"bufferSize"`
+	// DropPolicy is "block" (default) or "drop", selecting what happens
+	// to a new entry when the buffer is full.
+	DropPolicy string `yaml:"dropPolicy"`
+	// TLSClientCertFields is an allow-list of client-certificate DN
+	// components to render into ClientCertSubject/ClientCertIssuer, drawn
+	// from "CN", "O", "OU", "C", "L", "ST", "SERIALNUMBER".
+	TLSClientCertFields []string `yaml:"tlsClientCertFields"`
 }
 
 // CreateConfig creates the default plugin configuration.
@@ -20,89 +63,268 @@ func CreateConfig() *Config {
 	return &Config{}
 }
 
+// Stats reports runtime counters for a RequestLogger.
+type Stats struct {
+	// Dropped is the number of entries discarded because the buffer was
+	// full and DropPolicy is "drop".
+	Dropped uint64
+}
+
 type RequestLogger struct {
-	mu       sync.Mutex
-	file     *os.File
-	next     http.Handler
-	buffer   []string
-	interval string
+	mu          sync.Mutex
+	file        *os.File
+	path        string
+	next        http.Handler
+	formatter   logFormatter
+	filters     *compiledFilters
+	entries     chan string
+	flushSignal chan struct{}
+	highWater   int
+	dropPolicy  dropPolicy
+	tlsDNFields []string
+	dropped     uint64
 }
 
-func (e *RequestLogger) start(ctx context.Context) {
-	defer e.Close()
-	defer e.Flush()
+// New creates a new AccessLog plugin.
+func New(ctx context.Context, next http.Handler, config *Config, _ string) (http.Handler, error) {
+	interval, err := time.ParseDuration(config.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval %q: %w", config.Interval, err)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid interval %q: must be positive", config.Interval)
+	}
 
-	// parse the interval
-	interval, err := time.ParseDuration(e.interval)
+	policy, err := parseDropPolicy(config.DropPolicy)
+	if err != nil {
+		return nil, err
+	}
 
-	// interval is not valid, flush the buffer every time the buffer is not empty
+	filters, err := parseFilters(config.Filters)
 	if err != nil {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				if len(e.buffer) > 0 {
-					e.Flush()
-				}
-			}
+		return nil, err
+	}
+
+	var file *os.File
+	if config.Path == "" {
+		file = os.Stdout
+	} else {
+		file, err = os.OpenFile(config.Path, logFileFlags, logFilePerm)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	// interval is valid, flush the buffer every interval
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	instance := RequestLogger{
+		file:        file,
+		path:        config.Path,
+		next:        next,
+		formatter:   newFormatter(config.Format),
+		filters:     filters,
+		entries:     make(chan string, bufferSize),
+		flushSignal: make(chan struct{}, 1),
+		highWater:   highWaterMark(bufferSize),
+		dropPolicy:  policy,
+		tlsDNFields: config.TLSClientCertFields,
+	}
+
+	go instance.start(ctx, interval)
+	if instance.path != "" {
+		go instance.watchReopenSignal(ctx)
+	}
+	return &instance, nil
+}
+
+func parseDropPolicy(value string) (dropPolicy, error) {
+	switch dropPolicy(value) {
+	case "":
+		return dropPolicyBlock, nil
+	case dropPolicyBlock, dropPolicyDrop:
+		return dropPolicy(value), nil
+	default:
+		return "", fmt.Errorf("invalid dropPolicy %q: must be %q or %q", value, dropPolicyBlock, dropPolicyDrop)
+	}
+}
+
+// highWaterMark is the queue depth at which the writer goroutine flushes
+// early instead of waiting for the next ticker fire.
+func highWaterMark(bufferSize int) int {
+	mark := bufferSize * 3 / 4
+	if mark < 1 {
+		mark = 1
+	}
+	return mark
+}
+
+// start drains e.entries to the log file, flushing whenever the ticker
+// fires or the queue has filled to its high-water mark, whichever comes
+// first.
+func (e *RequestLogger) start(ctx context.Context, interval time.Duration) {
+	defer e.Close()
+	defer e.Flush()
+
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			e.Flush()
+		case <-e.flushSignal:
+			e.Flush()
 		}
 	}
 }
 
-// New creates a new AccessLog plugin.
-func New(ctx context.Context, next http.Handler, config *Config, _ string) (http.Handler, error) {
-	var file *os.File
-	if config.Path == "" {
-		file = os.Stdout
-	} else {
-		var err error
-		file, err = os.OpenFile(config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-		if err != nil {
-			return nil, err
+// watchReopenSignal reopens the log file whenever the process receives
+// SIGUSR1, the same signal logrotate sends after rotating a file out from
+// under a long-running process.
+func (e *RequestLogger) watchReopenSignal(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			e.Reopen()
 		}
 	}
-	instance := RequestLogger{
-		file:     file,
-		next:     next,
-		interval: config.Interval,
+}
+
+// Reopen closes the current log file and opens e.path again, so a file
+// renamed or truncated out from under the plugin (e.g. by logrotate) gets
+// a fresh descriptor. Entries already queued in e.entries are unaffected
+// and get written through whichever descriptor is current when the writer
+// goroutine next flushes. It is a no-op when the logger is writing to
+// stdout (no Path configured), since there is no file to rotate.
+func (e *RequestLogger) Reopen() error {
+	if e.path == "" {
+		return nil
 	}
 
-	go instance.start(ctx)
-	return &instance, nil
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.file.Close(); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(e.path, logFileFlags, logFilePerm)
+	if err != nil {
+		return err
+	}
+	e.file = file
+	return nil
 }
 
 func (e *RequestLogger) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	start := time.Now()
+	capture := newCaptureResponseWriter(rw)
 	defer func() {
-		e.mu.Lock()
-		defer e.mu.Unlock()
-		e.buffer = append(e.buffer, fmt.Sprintf("%s [%s] %s %s %s %s", req.RemoteAddr, start.Format(time.RFC3339), req.Method, req.Host, req.URL.Path, time.Since(start).String()))
+		entry := &logEntry{
+			ClientAddr:            req.RemoteAddr,
+			ClientHost:            clientHost(req.RemoteAddr),
+			RequestMethod:         req.Method,
+			RequestHost:           req.Host,
+			RequestPath:           req.URL.Path,
+			RequestProtocol:       req.Proto,
+			RequestScheme:         requestScheme(req),
+			RequestReferer:        req.Referer(),
+			RequestUserAgent:      req.UserAgent(),
+			DownstreamStatus:      capture.status,
+			DownstreamContentSize: capture.size,
+			Duration:              time.Since(start).Nanoseconds(),
+			StartUTC:              start.UTC(),
+			RetryAttempts:         retryAttempts(req),
+		}
+		populateTLSFields(entry, req, e.tlsDNFields)
+
+		if !e.filters.matches(entry) {
+			return
+		}
+
+		e.enqueue(e.formatter.Format(entry))
 	}()
-	e.next.ServeHTTP(rw, req)
+	e.next.ServeHTTP(capture, req)
+}
+
+// enqueue adds a formatted line to the buffer, honoring e.dropPolicy when
+// it is full, and nudges the writer goroutine once the queue reaches its
+// high-water mark.
+func (e *RequestLogger) enqueue(line string) {
+	switch e.dropPolicy {
+	case dropPolicyDrop:
+		select {
+		case e.entries <- line:
+		default:
+			atomic.AddUint64(&e.dropped, 1)
+			return
+		}
+	default:
+		e.entries <- line
+	}
+
+	if len(e.entries) >= e.highWater {
+		select {
+		case e.flushSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func requestScheme(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
 }
 
+// retryAttemptsHeader is the request header a retry middleware upstream of
+// this plugin is expected to set with the number of attempts already made,
+// so it can be recorded in the log entry and matched by Filters.RetryAttempts.
+const retryAttemptsHeader = "X-Retry-Attempts"
+
+func retryAttempts(req *http.Request) int {
+	n, err := strconv.Atoi(req.Header.Get(retryAttemptsHeader))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Flush synchronously writes every entry currently queued in e.entries to
+// the log file.
 func (e *RequestLogger) Flush() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	for _, line := range e.buffer {
-		e.file.WriteString(line + "\n")
+
+	for {
+		select {
+		case line := <-e.entries:
+			e.file.WriteString(line + "\n")
+		default:
+			return
+		}
 	}
-	e.buffer = nil
+}
+
+// Stats returns a snapshot of the logger's runtime counters.
+func (e *RequestLogger) Stats() Stats {
+	return Stats{Dropped: atomic.LoadUint64(&e.dropped)}
 }
 
 func (e *RequestLogger) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	return e.file.Close()
 }
@@ -2,6 +2,7 @@ package traefikrequestlogger
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -37,7 +38,6 @@ func TestNew_WithStdout(t *testing.T) {
 
 	accessLog := handler.(*RequestLogger)
 	assert.Equal(t, os.Stdout, accessLog.file)
-	assert.Equal(t, "1s", accessLog.interval)
 }
 
 func TestNew_WithFile(t *testing.T) {
@@ -60,7 +60,6 @@ func TestNew_WithFile(t *testing.T) {
 	accessLog := handler.(*RequestLogger)
 	assert.NotNil(t, accessLog.file)
 	assert.NotEqual(t, os.Stdout, accessLog.file)
-	assert.Equal(t, "500ms", accessLog.interval)
 
 	// Cleanup
 	accessLog.Close()
@@ -79,6 +78,47 @@ func TestNew_WithInvalidFile(t *testing.T) {
 	assert.Nil(t, handler)
 }
 
+func TestNew_WithInvalidInterval(t *testing.T) {
+	ctx := context.Background()
+	config := &Config{
+		Interval: "not-a-duration",
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	handler, err := New(ctx, next, config, "test")
+	assert.Error(t, err)
+	assert.Nil(t, handler)
+}
+
+func TestNew_WithNonPositiveInterval(t *testing.T) {
+	for _, interval := range []string{"0", "0s", "-1s"} {
+		t.Run(interval, func(t *testing.T) {
+			ctx := context.Background()
+			config := &Config{
+				Interval: interval,
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			handler, err := New(ctx, next, config, "test")
+			assert.Error(t, err)
+			assert.Nil(t, handler)
+		})
+	}
+}
+
+func TestNew_WithInvalidDropPolicy(t *testing.T) {
+	ctx := context.Background()
+	config := &Config{
+		Interval:   "1s",
+		DropPolicy: "bogus",
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	handler, err := New(ctx, next, config, "test")
+	assert.Error(t, err)
+	assert.Nil(t, handler)
+}
+
 func TestServeHTTP(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "access.log")
@@ -108,9 +148,9 @@ func TestServeHTTP(t *testing.T) {
 	assert.Equal(t, "OK", rr.Body.String())
 
 	accessLog := handler.(*RequestLogger)
-	// Wait a bit for the buffer to be populated
+	// Wait a bit for the entry to be queued
 	time.Sleep(50 * time.Millisecond)
-	assert.Greater(t, len(accessLog.buffer), 0)
+	assert.Greater(t, len(accessLog.entries), 0)
 
 	// Wait for flush
 	time.Sleep(200 * time.Millisecond)
@@ -119,9 +159,8 @@ func TestServeHTTP(t *testing.T) {
 	content, err := os.ReadFile(logPath)
 	require.NoError(t, err)
 	logContent := string(content)
-	assert.Contains(t, logContent, "192.168.1.1:12345")
+	assert.Contains(t, logContent, "192.168.1.1")
 	assert.Contains(t, logContent, "GET")
-	assert.Contains(t, logContent, "example.com")
 	assert.Contains(t, logContent, "/test")
 
 	accessLog.Close()
@@ -135,14 +174,17 @@ func TestFlush(t *testing.T) {
 	require.NoError(t, err)
 
 	accessLog := &RequestLogger{
-		file:   file,
-		buffer: []string{"log line 1", "log line 2", "log line 3"},
+		file:    file,
+		entries: make(chan string, 3),
 	}
+	accessLog.entries <- "log line 1"
+	accessLog.entries <- "log line 2"
+	accessLog.entries <- "log line 3"
 
 	accessLog.Flush()
 
-	// Verify buffer is cleared
-	assert.Equal(t, 0, len(accessLog.buffer))
+	// Verify the queue is drained
+	assert.Equal(t, 0, len(accessLog.entries))
 
 	// Verify content was written
 	content, err := os.ReadFile(logPath)
@@ -163,14 +205,14 @@ func TestFlush_EmptyBuffer(t *testing.T) {
 	require.NoError(t, err)
 
 	accessLog := &RequestLogger{
-		file:   file,
-		buffer: []string{},
+		file:    file,
+		entries: make(chan string, 1),
 	}
 
 	accessLog.Flush()
 
-	// Verify buffer is still empty
-	assert.Equal(t, 0, len(accessLog.buffer))
+	// Verify the queue is still empty
+	assert.Equal(t, 0, len(accessLog.entries))
 
 	// Verify file is empty or unchanged
 	content, err := os.ReadFile(logPath)
@@ -200,7 +242,7 @@ func TestClose(t *testing.T) {
 	assert.Contains(t, err.Error(), "closed")
 }
 
-func TestStart_WithValidInterval(t *testing.T) {
+func TestStart_FlushesOnTicker(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "access.log")
 
@@ -211,15 +253,18 @@ func TestStart_WithValidInterval(t *testing.T) {
 	defer cancel()
 
 	accessLog := &RequestLogger{
-		file:     file,
-		interval: "100ms",
-		buffer:   []string{"test log 1", "test log 2"},
+		file:        file,
+		entries:     make(chan string, 8),
+		flushSignal: make(chan struct{}, 1),
+		highWater:   highWaterMark(8),
 	}
+	accessLog.entries <- "test log 1"
+	accessLog.entries <- "test log 2"
 
-	go accessLog.start(ctx)
+	go accessLog.start(ctx, 50*time.Millisecond)
 
 	// Wait for flush
-	time.Sleep(200 * time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
 
 	// Verify logs were flushed
 	content, err := os.ReadFile(logPath)
@@ -228,11 +273,11 @@ func TestStart_WithValidInterval(t *testing.T) {
 	assert.Contains(t, logContent, "test log 1")
 	assert.Contains(t, logContent, "test log 2")
 
-	// Verify buffer is cleared
-	assert.Equal(t, 0, len(accessLog.buffer))
+	// Verify the queue is drained
+	assert.Equal(t, 0, len(accessLog.entries))
 }
 
-func TestStart_WithInvalidInterval(t *testing.T) {
+func TestStart_FlushesOnHighWaterMark(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "access.log")
 
@@ -240,33 +285,28 @@ func TestStart_WithInvalidInterval(t *testing.T) {
 	require.NoError(t, err)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	accessLog := &RequestLogger{
-		file:     file,
-		interval: "invalid-interval",
-		buffer:   []string{"test log"},
+		file:        file,
+		entries:     make(chan string, 4),
+		flushSignal: make(chan struct{}, 1),
+		highWater:   highWaterMark(4),
+		dropPolicy:  dropPolicyBlock,
 	}
 
-	go accessLog.start(ctx)
-
-	// Wait a bit
-	time.Sleep(50 * time.Millisecond)
-
-	// With invalid interval, it should flush when buffer is not empty
-	// The goroutine continuously checks if buffer is not empty
-	time.Sleep(100 * time.Millisecond)
-
-	// Verify logs were flushed
-	content, err := os.ReadFile(logPath)
-	require.NoError(t, err)
-	logContent := string(content)
-	assert.Contains(t, logContent, "test log")
+	go accessLog.start(ctx, time.Hour) // ticker never fires in this test
 
-	// Verify buffer is cleared
-	assert.Equal(t, 0, len(accessLog.buffer))
+	for i := 0; i < 4; i++ {
+		accessLog.enqueue(fmt.Sprintf("test log %d", i))
+	}
 
-	cancel()
-	time.Sleep(50 * time.Millisecond)
+	// The high-water mark should have triggered a flush well before the
+	// ticker would ever fire.
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(logPath)
+		return err == nil && len(content) > 0
+	}, time.Second, 10*time.Millisecond)
 }
 
 func TestServeHTTP_LogFormat(t *testing.T) {
@@ -305,20 +345,78 @@ func TestServeHTTP_LogFormat(t *testing.T) {
 	require.Greater(t, len(lines), 0)
 
 	logLine := lines[0]
-	parts := strings.Fields(logLine)
-	require.GreaterOrEqual(t, len(parts), 6)
 
-	// Format: RemoteAddr [Timestamp] Method Host Path Duration
-	assert.Equal(t, "10.0.0.1:54321", parts[0])
-	assert.Contains(t, logLine, "POST")
-	assert.Contains(t, logLine, "example.com")
-	assert.Contains(t, logLine, "/api/users")
-	assert.Contains(t, logLine, "[") // Timestamp format
-	assert.Contains(t, logLine, "]")
+	// Common Log Format: remoteHost - user [time] "method path proto" status size
+	assert.True(t, strings.HasPrefix(logLine, "10.0.0.1 - - ["))
+	assert.Contains(t, logLine, `"POST /api/users HTTP/1.1"`)
 
 	handler.(*RequestLogger).Close()
 }
 
+func TestServeHTTP_DropPolicyDropCountsStats(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "access.log")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := &Config{
+		Path:       logPath,
+		Interval:   time.Hour.String(), // ticker never fires in this test
+		BufferSize: 1,
+		DropPolicy: "drop",
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(ctx, next, config, "test")
+	require.NoError(t, err)
+	accessLog := handler.(*RequestLogger)
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+		rr := httptest.NewRecorder()
+		accessLog.ServeHTTP(rr, req)
+	}
+
+	assert.Greater(t, accessLog.Stats().Dropped, uint64(0))
+	accessLog.Close()
+}
+
+func TestServeHTTP_FiltersDropNonMatching(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "access.log")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := &Config{
+		Path:     logPath,
+		Interval: "20ms",
+		Filters:  &Filters{StatusCodes: []string{"500-599"}},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(ctx, next, config, "test")
+	require.NoError(t, err)
+	accessLog := handler.(*RequestLogger)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/healthy", nil)
+	rr := httptest.NewRecorder()
+	accessLog.ServeHTTP(rr, req)
+
+	time.Sleep(50 * time.Millisecond)
+	accessLog.Close()
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "", string(content))
+}
+
 func TestMultipleRequests(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "access.log")
@@ -414,15 +512,16 @@ func TestFlush_ConcurrentAccess(t *testing.T) {
 	require.NoError(t, err)
 
 	accessLog := &RequestLogger{
-		file:   file,
-		buffer: []string{},
+		file:       file,
+		entries:    make(chan string, 10),
+		dropPolicy: dropPolicyBlock,
 	}
 
 	// Add items concurrently
 	done := make(chan bool, 10)
 	for i := 0; i < 10; i++ {
 		go func(id int) {
-			accessLog.buffer = append(accessLog.buffer, "log line")
+			accessLog.entries <- "log line"
 			accessLog.Flush()
 			done <- true
 		}(i)
@@ -0,0 +1,127 @@
+package traefikrequestlogger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReopen_RotatesWithoutLosingLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "access.log")
+	rotatedPath := logPath + ".rotated"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := &Config{
+		Path:     logPath,
+		Interval: "10ms",
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(ctx, next, config, "test")
+	require.NoError(t, err)
+	accessLog := handler.(*RequestLogger)
+
+	const before, after = 5, 5
+
+	fire := func(n int) {
+		for i := 0; i < n; i++ {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://example.com/%d", i), nil)
+			req.RemoteAddr = "192.168.1.1:12345"
+			rr := httptest.NewRecorder()
+			accessLog.ServeHTTP(rr, req)
+		}
+	}
+
+	fire(before)
+	time.Sleep(30 * time.Millisecond) // let the ticker flush `before` to disk
+
+	require.NoError(t, os.Rename(logPath, rotatedPath))
+	require.NoError(t, accessLog.Reopen())
+
+	fire(after)
+	time.Sleep(30 * time.Millisecond) // let the ticker flush `after` to disk
+
+	accessLog.Close()
+
+	rotatedContent, err := os.ReadFile(rotatedPath)
+	require.NoError(t, err)
+	currentContent, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	totalLines := countLines(rotatedContent) + countLines(currentContent)
+	require.Equal(t, before+after, totalLines)
+}
+
+func TestReopen_NoopOnStdout(t *testing.T) {
+	// Deliberately not cancelled: cancelling would make start()'s shutdown
+	// path call Close() on the process-wide os.Stdout, same as
+	// TestNew_WithStdout avoids.
+	ctx := context.Background()
+
+	config := &Config{
+		Interval: "10ms",
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(ctx, next, config, "test")
+	require.NoError(t, err)
+	accessLog := handler.(*RequestLogger)
+
+	require.NoError(t, accessLog.Reopen())
+
+	// Stdout must still be open and usable.
+	_, err = os.Stdout.WriteString("")
+	require.NoError(t, err)
+	require.Equal(t, os.Stdout, accessLog.file)
+}
+
+func TestReopenAndClose_NoRace(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "access.log")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := &Config{
+		Path:     logPath,
+		Interval: "10ms",
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(ctx, next, config, "test")
+	require.NoError(t, err)
+	accessLog := handler.(*RequestLogger)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		accessLog.Reopen()
+	}()
+	accessLog.Close()
+	<-done
+}
+
+func countLines(content []byte) int {
+	trimmed := strings.TrimSpace(string(content))
+	if trimmed == "" {
+		return 0
+	}
+	return len(strings.Split(trimmed, "\n"))
+}
@@ -0,0 +1,86 @@
+package traefikrequestlogger
+
+import (
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// dnComponents maps an allow-listed name to the pkix.Name values it
+// extracts, so Config.TLSClientCertFields can select which Subject/Issuer
+// DN components get rendered into the log entry.
+var dnComponents = map[string]func(pkix.Name) []string{
+	"CN":           func(n pkix.Name) []string { return stringOrNil(n.CommonName) },
+	"SERIALNUMBER": func(n pkix.Name) []string { return stringOrNil(n.SerialNumber) },
+	"O":            func(n pkix.Name) []string { return n.Organization },
+	"OU":           func(n pkix.Name) []string { return n.OrganizationalUnit },
+	"C":            func(n pkix.Name) []string { return n.Country },
+	"L":            func(n pkix.Name) []string { return n.Locality },
+	"ST":           func(n pkix.Name) []string { return n.Province },
+}
+
+func stringOrNil(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// renderDN renders the allow-listed components of name as a stable,
+// openssl-style DN string, e.g. "CN=alice,OU=eng,O=example".
+func renderDN(name pkix.Name, fields []string) string {
+	var parts []string
+	for _, field := range fields {
+		extract, ok := dnComponents[field]
+		if !ok {
+			continue
+		}
+		for _, value := range extract(name) {
+			parts = append(parts, field+"="+value)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// tlsVersionName renders a tls.VersionTLS* constant the way OpenSSL and
+// Traefik's own access log do, e.g. "TLS1.3".
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS1.0"
+	case tls.VersionTLS11:
+		return "TLS1.1"
+	case tls.VersionTLS12:
+		return "TLS1.2"
+	case tls.VersionTLS13:
+		return "TLS1.3"
+	default:
+		return ""
+	}
+}
+
+// populateTLSFields fills in entry's TLS and client-certificate fields from
+// req.TLS, rendering Subject/Issuer DNs restricted to dnFields. It is a
+// no-op when the request wasn't served over TLS.
+func populateTLSFields(entry *logEntry, req *http.Request, dnFields []string) {
+	if req.TLS == nil {
+		return
+	}
+
+	entry.TLSVersion = tlsVersionName(req.TLS.Version)
+	entry.TLSCipher = tls.CipherSuiteName(req.TLS.CipherSuite)
+
+	if len(req.TLS.PeerCertificates) == 0 {
+		return
+	}
+
+	leaf := req.TLS.PeerCertificates[0]
+	entry.ClientCertCN = leaf.Subject.CommonName
+	entry.ClientCertOU = strings.Join(leaf.Subject.OrganizationalUnit, ",")
+	entry.ClientCertSerial = leaf.SerialNumber.String()
+	entry.ClientCertNotAfter = leaf.NotAfter.UTC().Format(time.RFC3339)
+	entry.ClientCertSubject = renderDN(leaf.Subject, dnFields)
+	entry.ClientCertIssuer = renderDN(leaf.Issuer, dnFields)
+}
@@ -0,0 +1,77 @@
+package traefikrequestlogger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderDN(t *testing.T) {
+	name := pkix.Name{
+		CommonName:         "alice",
+		Organization:       []string{"Example Corp"},
+		OrganizationalUnit: []string{"Engineering"},
+		Country:            []string{"US"},
+	}
+
+	assert.Equal(t, "CN=alice,OU=Engineering", renderDN(name, []string{"CN", "OU"}))
+	assert.Equal(t, "", renderDN(name, nil))
+	assert.Equal(t, "", renderDN(name, []string{"UNKNOWN"}))
+}
+
+func TestTLSVersionName(t *testing.T) {
+	assert.Equal(t, "TLS1.2", tlsVersionName(tls.VersionTLS12))
+	assert.Equal(t, "TLS1.3", tlsVersionName(tls.VersionTLS13))
+	assert.Equal(t, "", tlsVersionName(0x9999))
+}
+
+func TestPopulateTLSFields_NoTLS(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	entry := &logEntry{}
+
+	populateTLSFields(entry, req, nil)
+
+	assert.Empty(t, entry.TLSVersion)
+	assert.Empty(t, entry.ClientCertCN)
+}
+
+func TestPopulateTLSFields_WithClientCert(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject: pkix.Name{
+			CommonName:         "client.example.com",
+			OrganizationalUnit: []string{"eng", "sre"},
+		},
+		Issuer: pkix.Name{
+			CommonName: "Example CA",
+		},
+		NotAfter: notAfter,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	req.TLS = &tls.ConnectionState{
+		Version:          tls.VersionTLS13,
+		CipherSuite:      tls.TLS_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+
+	entry := &logEntry{}
+	populateTLSFields(entry, req, []string{"CN"})
+
+	assert.Equal(t, "TLS1.3", entry.TLSVersion)
+	assert.Equal(t, "TLS_AES_128_GCM_SHA256", entry.TLSCipher)
+	assert.Equal(t, "client.example.com", entry.ClientCertCN)
+	assert.Equal(t, "eng,sre", entry.ClientCertOU)
+	assert.Equal(t, "42", entry.ClientCertSerial)
+	assert.Equal(t, "2030-01-01T00:00:00Z", entry.ClientCertNotAfter)
+	assert.Equal(t, "CN=client.example.com", entry.ClientCertSubject)
+	assert.Equal(t, "CN=Example CA", entry.ClientCertIssuer)
+}